@@ -0,0 +1,94 @@
+package gerber
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/thomasesr/kcdb/src/kcdb/mod"
+)
+
+// hasDrills reports whether m has at least one pad with a drill hole.
+func hasDrills(m *mod.Module) bool {
+	for _, p := range m.Pads {
+		if drillDiameter(p.Drill) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// drillDiameter returns the usable drill diameter for d, handling both
+// a plain scalar hole and the oval/ellipse kind, where the smaller of
+// the two ellipse parameters is the drill's width.
+func drillDiameter(d mod.Drill) float64 {
+	switch {
+	case d.Kind == "oval":
+		return math.Min(d.Ellipse.X, d.Ellipse.Y)
+	case d.Scalar > 0:
+		return d.Scalar
+	default:
+		return 0
+	}
+}
+
+// writeExcellon writes an Excellon drill file for every drilled pad in m.
+func writeExcellon(path string, m *mod.Module) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tools := drillTools(m)
+
+	bw := bufio.NewWriter(f)
+	fmt.Fprintf(bw, "M48\n")
+	fmt.Fprintf(bw, "METRIC,LZ\n")
+	for i, dia := range tools {
+		fmt.Fprintf(bw, "T%02dC%.3f\n", i+1, dia)
+	}
+	fmt.Fprintf(bw, "%%\n")
+
+	for i, dia := range tools {
+		fmt.Fprintf(bw, "T%02d\n", i+1)
+		for _, p := range m.Pads {
+			d := drillDiameter(p.Drill)
+			if d != dia {
+				continue
+			}
+			offset := mod.RotateVector(p.Drill.Offset, p.Rotation)
+			x, y := p.Pos.X+offset.X, p.Pos.Y+offset.Y
+			fmt.Fprintf(bw, "X%sY%s\n", excellonCoord(x), excellonCoord(y))
+		}
+	}
+
+	fmt.Fprintf(bw, "M30\n")
+	return bw.Flush()
+}
+
+// drillTools returns the distinct drill diameters used across m's
+// pads, sorted ascending, matching the Txx tool numbering Excellon
+// readers expect.
+func drillTools(m *mod.Module) []float64 {
+	seen := map[float64]bool{}
+	for _, p := range m.Pads {
+		if d := drillDiameter(p.Drill); d > 0 {
+			seen[d] = true
+		}
+	}
+	tools := make([]float64, 0, len(seen))
+	for d := range seen {
+		tools = append(tools, d)
+	}
+	sort.Float64s(tools)
+	return tools
+}
+
+// excellonCoord formats a coordinate (in mm) in the METRIC,LZ
+// (leading-zeros, 3 decimal place) format declared in the file header.
+func excellonCoord(v float64) string {
+	return fmt.Sprintf("%.3f", v)
+}
@@ -0,0 +1,244 @@
+// Package gerber converts a parsed KiCad module into fabrication-ready
+// RS-274X Gerber files and an Excellon drill file, without depending on
+// KiCad's own plotter.
+package gerber
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/thomasesr/kcdb/src/kcdb/mod"
+)
+
+// Options controls how a Module is exported.
+type Options struct {
+	// BaseName prefixes each generated file, e.g. "board" -> "board-F_Cu.gbr".
+	// Defaults to the Module's Name when empty.
+	BaseName string
+}
+
+// layerSuffix maps a KiCad layer name to the suffix KiCad itself uses
+// when plotting Gerbers, so downstream fab tools recognize the files.
+var layerSuffix = map[string]string{
+	"F.Cu":      "F_Cu.gbr",
+	"B.Cu":      "B_Cu.gbr",
+	"F.SilkS":   "F_SilkS.gbr",
+	"B.SilkS":   "B_SilkS.gbr",
+	"F.Mask":    "F_Mask.gbr",
+	"B.Mask":    "B_Mask.gbr",
+	"F.Paste":   "F_Paste.gbr",
+	"B.Paste":   "B_Paste.gbr",
+	"F.CrtYd":   "F_CrtYd.gbr",
+	"B.CrtYd":   "B_CrtYd.gbr",
+	"Edge.Cuts": "Edge_Cuts.gbr",
+}
+
+// Export writes one Gerber file per layer present in m, plus an
+// Excellon drill file for any pads with a drill hole, into dir. It
+// returns the paths of every file written.
+func Export(dir string, m *mod.Module, opts Options) ([]string, error) {
+	if opts.BaseName == "" {
+		opts.BaseName = m.Name
+	}
+	if opts.BaseName == "" {
+		opts.BaseName = "module"
+	}
+
+	layers := layersUsed(m)
+	var written []string
+
+	for _, layer := range layers {
+		suffix, ok := layerSuffix[layer]
+		if !ok {
+			suffix = sanitizeLayer(layer) + ".gbr"
+		}
+		path := filepath.Join(dir, opts.BaseName+"-"+suffix)
+		if err := writeGerberLayer(path, m, layer); err != nil {
+			return written, fmt.Errorf("gerber: writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	if hasDrills(m) {
+		path := filepath.Join(dir, opts.BaseName+".drl")
+		if err := writeExcellon(path, m); err != nil {
+			return written, fmt.Errorf("gerber: writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+func layersUsed(m *mod.Module) []string {
+	seen := map[string]bool{}
+	add := func(l string) {
+		if l != "" {
+			seen[l] = true
+		}
+	}
+	for _, l := range m.Lines {
+		add(l.Layer)
+	}
+	for _, r := range m.Rects {
+		add(r.Layer)
+	}
+	for _, a := range m.Arcs {
+		add(a.Layer)
+	}
+	for _, c := range m.Circles {
+		add(c.Layer)
+	}
+	for _, p := range m.Polygons {
+		add(p.Layer)
+	}
+	for _, t := range m.Texts {
+		add(t.Layer)
+	}
+	for _, p := range m.Pads {
+		for _, l := range p.Layers {
+			add(l)
+		}
+	}
+
+	layers := make([]string, 0, len(seen))
+	for l := range seen {
+		layers = append(layers, l)
+	}
+	sort.Strings(layers)
+	return layers
+}
+
+func sanitizeLayer(layer string) string {
+	out := []rune(layer)
+	for i, r := range out {
+		if r == '.' {
+			out[i] = '_'
+		}
+	}
+	return string(out)
+}
+
+func writeGerberLayer(path string, m *mod.Module, layer string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	fmt.Fprintf(bw, "%%FSLAX46Y46*%%\n")
+	fmt.Fprintf(bw, "%%MOMM*%%\n")
+	fmt.Fprintf(bw, "G04 Generated by kcdb, layer %s*\n", layer)
+
+	// D10 is the single aperture used for strokes on this layer; KiCad
+	// defines one aperture per distinct stroke width, but a single
+	// round aperture keeps this exporter's output simple to verify.
+	fmt.Fprintf(bw, "%%ADD10C,0.1524*%%\n")
+	fmt.Fprintf(bw, "D10*\n")
+
+	for _, l := range m.Lines {
+		if l.Layer != layer {
+			continue
+		}
+		writeGerberStroke(bw, []mod.Point2D{l.Start, l.End})
+	}
+	for _, r := range m.Rects {
+		if r.Layer != layer {
+			continue
+		}
+		writeGerberStroke(bw, []mod.Point2D{
+			r.Start, {X: r.End.X, Y: r.Start.Y}, r.End, {X: r.Start.X, Y: r.End.Y}, r.Start,
+		})
+	}
+	for _, a := range m.Arcs {
+		if a.Layer != layer {
+			continue
+		}
+		writeGerberStroke(bw, a.Sample(32))
+	}
+	for _, c := range m.Circles {
+		if c.Layer != layer {
+			continue
+		}
+		r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+		writeGerberStroke(bw, circlePoints(c.Center, r))
+	}
+	for _, p := range m.Polygons {
+		if p.Layer != layer {
+			continue
+		}
+		outline := p.Outline()
+		pts := make([]mod.Point2D, len(outline)+1)
+		copy(pts, outline)
+		if len(outline) > 0 {
+			pts[len(outline)] = outline[0]
+		}
+		writeGerberStroke(bw, pts)
+	}
+	for _, p := range m.Pads {
+		if !hasLayer(p.Layers, layer) {
+			continue
+		}
+		writeGerberPad(bw, p)
+	}
+
+	fmt.Fprintf(bw, "M02*\n")
+	return bw.Flush()
+}
+
+func writeGerberStroke(bw *bufio.Writer, pts []mod.Point2D) {
+	if len(pts) == 0 {
+		return
+	}
+	fmt.Fprintf(bw, "X%sY%sD02*\n", gerberCoord(pts[0].X), gerberCoord(pts[0].Y))
+	for _, p := range pts[1:] {
+		fmt.Fprintf(bw, "X%sY%sD01*\n", gerberCoord(p.X), gerberCoord(p.Y))
+	}
+}
+
+func writeGerberPad(bw *bufio.Writer, p mod.Pad) {
+	if p.Shape == "circle" && p.Rotation == 0 {
+		writeGerberStroke(bw, circlePoints(p.Pos, p.Size.X/2))
+		return
+	}
+	// Outline() already translates by Pos and rotates by Rotation, so
+	// this traces the pad's true placement instead of an axis-aligned box.
+	outline := p.Outline()
+	pts := make([]mod.Point2D, len(outline)+1)
+	copy(pts, outline)
+	if len(outline) > 0 {
+		pts[len(outline)] = outline[0]
+	}
+	writeGerberStroke(bw, pts)
+}
+
+func circlePoints(center mod.Point2D, r float64) []mod.Point2D {
+	const steps = 32
+	pts := make([]mod.Point2D, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		t := 2 * math.Pi * float64(i) / steps
+		pts = append(pts, mod.Point2D{X: center.X + r*math.Cos(t), Y: center.Y + r*math.Sin(t)})
+	}
+	return pts
+}
+
+func hasLayer(layers []string, layer string) bool {
+	for _, l := range layers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// gerberCoord formats a coordinate (in mm) as the 4.6 integer format
+// declared by %FSLAX46Y46*%: six implied decimal places, leading zeros
+// omitted.
+func gerberCoord(v float64) string {
+	return fmt.Sprintf("%d", int64(math.Round(v*1e6)))
+}
@@ -0,0 +1,309 @@
+package mod
+
+import "math"
+
+// Sample approximates the arc as a polyline of the given number of
+// steps, using End as the arc's center (as stored by DecodeModule) and
+// Start as the point the sweep of Angle degrees begins from.
+func (a FpArc) Sample(steps int) []Point2D {
+	if steps < 1 {
+		steps = 1
+	}
+	cx, cy := a.End.X, a.End.Y
+	radius := math.Hypot(a.Start.X-cx, a.Start.Y-cy)
+	startAngle := math.Atan2(a.Start.Y-cy, a.Start.X-cx)
+	sweep := a.Angle * math.Pi / 180
+
+	pts := make([]Point2D, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		t := startAngle + sweep*float64(i)/float64(steps)
+		pts = append(pts, Point2D{X: cx + radius*math.Cos(t), Y: cy + radius*math.Sin(t)})
+	}
+	return pts
+}
+
+// Outline returns the pad's outline as a closed polygon in board
+// coordinates (i.e. translated by Pos and rotated by Rotation).
+func (p Pad) Outline() []Point2D {
+	hw, hh := p.Size.X/2, p.Size.Y/2
+	switch p.Shape {
+	case "circle", "oval":
+		const steps = 32
+		pts := make([]Point2D, 0, steps)
+		for i := 0; i < steps; i++ {
+			t := 2 * math.Pi * float64(i) / steps
+			pts = append(pts, rotateAbout(Point2D{X: p.Pos.X + hw*math.Cos(t), Y: p.Pos.Y + hh*math.Sin(t)}, p.Pos, p.Rotation))
+		}
+		return pts
+	default: // "rect", "roundrect", and anything else fall back to a rectangle
+		corners := []Point2D{
+			{X: p.Pos.X - hw, Y: p.Pos.Y - hh},
+			{X: p.Pos.X + hw, Y: p.Pos.Y - hh},
+			{X: p.Pos.X + hw, Y: p.Pos.Y + hh},
+			{X: p.Pos.X - hw, Y: p.Pos.Y + hh},
+		}
+		for i, c := range corners {
+			corners[i] = rotateAbout(c, p.Pos, p.Rotation)
+		}
+		return corners
+	}
+}
+
+// Outline returns the polygon's points in board coordinates (i.e.
+// translated by At and rotated by Rotation).
+func (p FpPoly) Outline() []Point2D {
+	pts := make([]Point2D, len(p.Points))
+	for i, pt := range p.Points {
+		pts[i] = rotateAbout(Point2D{X: p.At.X + pt.X, Y: p.At.Y + pt.Y}, p.At, p.Rotation)
+	}
+	return pts
+}
+
+// rotateAbout rotates p by degrees (counter-clockwise, standard math
+// convention) around center.
+func rotateAbout(p, center Point2D, degrees float64) Point2D {
+	if degrees == 0 {
+		return p
+	}
+	rad := degrees * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	dx, dy := p.X-center.X, p.Y-center.Y
+	return Point2D{X: center.X + dx*cos - dy*sin, Y: center.Y + dx*sin + dy*cos}
+}
+
+// RotateVector rotates v (a direction, not a point) by degrees
+// (counter-clockwise, standard math convention) around the origin, so
+// e.g. a pad's drill offset follows the pad's own Rotation.
+func RotateVector(v Point2D, degrees float64) Point2D {
+	if degrees == 0 {
+		return v
+	}
+	rad := degrees * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	return Point2D{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos}
+}
+
+// BoundingBox returns the axis-aligned bounding box over every line,
+// rect, arc, circle, polygon and pad in the module, regardless of
+// layer. Texts are not included.
+func (m *Module) BoundingBox() (min, max Point2D) {
+	return m.layerBoundingBox(nil)
+}
+
+// LayerBoundingBox returns the axis-aligned bounding box over the
+// lines, rects, arcs, circles, polygons and pads present on layer.
+// Texts are not included.
+func (m *Module) LayerBoundingBox(layer string) (min, max Point2D) {
+	return m.layerBoundingBox(&layer)
+}
+
+func (m *Module) layerBoundingBox(layer *string) (min, max Point2D) {
+	min = Point2D{X: math.MaxFloat64, Y: math.MaxFloat64}
+	max = Point2D{X: -math.MaxFloat64, Y: -math.MaxFloat64}
+	matches := func(l string) bool { return layer == nil || *layer == l }
+
+	consider := func(p Point2D) {
+		min.X, min.Y = math.Min(min.X, p.X), math.Min(min.Y, p.Y)
+		max.X, max.Y = math.Max(max.X, p.X), math.Max(max.Y, p.Y)
+	}
+	for _, l := range m.Lines {
+		if matches(l.Layer) {
+			consider(l.Start)
+			consider(l.End)
+		}
+	}
+	for _, r := range m.Rects {
+		if matches(r.Layer) {
+			consider(r.Start)
+			consider(r.End)
+		}
+	}
+	for _, a := range m.Arcs {
+		if matches(a.Layer) {
+			for _, p := range a.Sample(16) {
+				consider(p)
+			}
+		}
+	}
+	for _, c := range m.Circles {
+		if matches(c.Layer) {
+			r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+			consider(Point2D{X: c.Center.X - r, Y: c.Center.Y - r})
+			consider(Point2D{X: c.Center.X + r, Y: c.Center.Y + r})
+		}
+	}
+	for _, poly := range m.Polygons {
+		if matches(poly.Layer) {
+			for _, p := range poly.Outline() {
+				consider(p)
+			}
+		}
+	}
+	for _, p := range m.Pads {
+		if layer == nil || hasLayer(p.Layers, *layer) {
+			for _, pt := range p.Outline() {
+				consider(pt)
+			}
+		}
+	}
+
+	if min.X > max.X {
+		return Point2D{}, Point2D{}
+	}
+	return min, max
+}
+
+func hasLayer(layers []string, layer string) bool {
+	for _, l := range layers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// CourtyardPolygon returns the convex hull of every point drawn on the
+// module's courtyard layers (F.CrtYd/B.CrtYd), which is the usual
+// approximation of a footprint's placement outline.
+func (m *Module) CourtyardPolygon() []Point2D {
+	var pts []Point2D
+	onCourtyard := func(l string) bool { return l == "F.CrtYd" || l == "B.CrtYd" }
+
+	for _, l := range m.Lines {
+		if onCourtyard(l.Layer) {
+			pts = append(pts, l.Start, l.End)
+		}
+	}
+	for _, r := range m.Rects {
+		if onCourtyard(r.Layer) {
+			pts = append(pts, r.Start, Point2D{X: r.End.X, Y: r.Start.Y}, r.End, Point2D{X: r.Start.X, Y: r.End.Y})
+		}
+	}
+	for _, a := range m.Arcs {
+		if onCourtyard(a.Layer) {
+			pts = append(pts, a.Sample(16)...)
+		}
+	}
+	for _, c := range m.Circles {
+		if onCourtyard(c.Layer) {
+			r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+			pts = append(pts,
+				Point2D{X: c.Center.X - r, Y: c.Center.Y - r},
+				Point2D{X: c.Center.X + r, Y: c.Center.Y - r},
+				Point2D{X: c.Center.X + r, Y: c.Center.Y + r},
+				Point2D{X: c.Center.X - r, Y: c.Center.Y + r},
+			)
+		}
+	}
+	for _, poly := range m.Polygons {
+		if onCourtyard(poly.Layer) {
+			pts = append(pts, poly.Outline()...)
+		}
+	}
+
+	return convexHull(pts)
+}
+
+// convexHull computes the convex hull of pts using the monotone chain
+// algorithm, returning points in counter-clockwise order.
+func convexHull(pts []Point2D) []Point2D {
+	if len(pts) < 3 {
+		return pts
+	}
+	sorted := append([]Point2D(nil), pts...)
+	sortPoints(sorted)
+
+	cross := func(o, a, b Point2D) float64 {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+
+	var hull []Point2D
+	for _, p := range sorted {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
+
+func sortPoints(pts []Point2D) {
+	for i := 1; i < len(pts); i++ {
+		for j := i; j > 0 && less(pts[j], pts[j-1]); j-- {
+			pts[j], pts[j-1] = pts[j-1], pts[j]
+		}
+	}
+}
+
+func less(a, b Point2D) bool {
+	if a.X != b.X {
+		return a.X < b.X
+	}
+	return a.Y < b.Y
+}
+
+// MinPadClearance returns the smallest edge-to-edge gap found between
+// any two pads' outlines, reduced by the module's Clearance and
+// SolderMaskMargin allowances, along with the indices (into Pads) of
+// the offending pair.
+func (m *Module) MinPadClearance() (clearance float64, i, j int) {
+	best := math.MaxFloat64
+	bi, bj := -1, -1
+
+	for a := 0; a < len(m.Pads); a++ {
+		outlineA := m.Pads[a].Outline()
+		for b := a + 1; b < len(m.Pads); b++ {
+			d := polygonGap(outlineA, m.Pads[b].Outline())
+			d -= m.Clearance + m.SolderMaskMargin
+			if d < best {
+				best, bi, bj = d, a, b
+			}
+		}
+	}
+
+	return best, bi, bj
+}
+
+// polygonGap returns the minimum distance between any vertex of a and
+// any edge of b (and vice versa), which for the convex, non-overlapping
+// pad outlines produced by Pad.Outline is the true edge-to-edge gap.
+func polygonGap(a, b []Point2D) float64 {
+	best := math.MaxFloat64
+	for _, p := range a {
+		best = math.Min(best, distToPolygon(p, b))
+	}
+	for _, p := range b {
+		best = math.Min(best, distToPolygon(p, a))
+	}
+	return best
+}
+
+func distToPolygon(p Point2D, poly []Point2D) float64 {
+	best := math.MaxFloat64
+	for i := range poly {
+		a := poly[i]
+		b := poly[(i+1)%len(poly)]
+		best = math.Min(best, distToSegment(p, a, b))
+	}
+	return best
+}
+
+func distToSegment(p, a, b Point2D) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	cx, cy := a.X+t*dx, a.Y+t*dy
+	return math.Hypot(p.X-cx, p.Y-cy)
+}
@@ -0,0 +1,102 @@
+package mod
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundingBoxIncludesRects(t *testing.T) {
+	m := &Module{Rects: []FpRect{{Start: Point2D{X: -2, Y: -3}, End: Point2D{X: 4, Y: 5}, Layer: "F.CrtYd"}}}
+
+	min, max := m.BoundingBox()
+	if min != (Point2D{X: -2, Y: -3}) || max != (Point2D{X: 4, Y: 5}) {
+		t.Fatalf("BoundingBox() = %+v, %+v; want {-2 -3}, {4 5}", min, max)
+	}
+}
+
+func TestCourtyardPolygonIncludesRects(t *testing.T) {
+	m := &Module{Rects: []FpRect{{Start: Point2D{X: -1, Y: -1}, End: Point2D{X: 1, Y: 1}, Layer: "F.CrtYd"}}}
+
+	hull := m.CourtyardPolygon()
+	if len(hull) == 0 {
+		t.Fatal("CourtyardPolygon() returned no points for a module containing only an fp_rect")
+	}
+}
+
+func TestPadOutlineRotation(t *testing.T) {
+	p := Pad{Shape: "rect", Pos: Point2D{X: 1, Y: 2}, Size: Point2D{X: 2, Y: 1}, Rotation: 90}
+
+	corners := p.Outline()
+	if len(corners) != 4 {
+		t.Fatalf("Outline() returned %d points, want 4", len(corners))
+	}
+
+	// A 90 degree rotation swaps the rect's half-width and half-height,
+	// so the outline's bounding box should come out as 1x2 instead of
+	// the unrotated pad's 2x1.
+	minX, minY := math.MaxFloat64, math.MaxFloat64
+	maxX, maxY := -math.MaxFloat64, -math.MaxFloat64
+	for _, c := range corners {
+		minX, minY = math.Min(minX, c.X), math.Min(minY, c.Y)
+		maxX, maxY = math.Max(maxX, c.X), math.Max(maxY, c.Y)
+	}
+	if w, h := maxX-minX, maxY-minY; math.Abs(w-1) > 1e-9 || math.Abs(h-2) > 1e-9 {
+		t.Fatalf("rotated Outline() bounding box = %gx%g, want 1x2", w, h)
+	}
+
+	// The pad's own position must still be the rotation pivot.
+	cx, cy := (minX+maxX)/2, (minY+maxY)/2
+	if math.Abs(cx-p.Pos.X) > 1e-9 || math.Abs(cy-p.Pos.Y) > 1e-9 {
+		t.Fatalf("rotated Outline() center = (%g, %g), want (%g, %g)", cx, cy, p.Pos.X, p.Pos.Y)
+	}
+}
+
+func TestBoundingBoxAccountsForPolygonRotation(t *testing.T) {
+	m := &Module{Polygons: []FpPoly{{
+		At:       Point2D{X: 0, Y: 0},
+		Rotation: 30,
+		Points:   []Point2D{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}},
+		Layer:    "F.SilkS",
+	}}}
+
+	min, max := m.BoundingBox()
+	// Rotating {1,0} by 30 degrees gives {cos30, sin30}, whose X is the
+	// largest among the rotated points; rotating {1,1} gives
+	// {cos30-sin30, sin30+cos30}, whose Y is the largest.
+	wantMaxX := math.Cos(30 * math.Pi / 180)
+	wantMaxY := math.Sin(30*math.Pi/180) + math.Cos(30*math.Pi/180)
+	if math.Abs(min.X) > 1e-9 || math.Abs(min.Y) > 1e-9 {
+		t.Fatalf("BoundingBox() min = %+v, want (0, 0)", min)
+	}
+	if math.Abs(max.X-wantMaxX) > 1e-9 || math.Abs(max.Y-wantMaxY) > 1e-9 {
+		t.Fatalf("BoundingBox() max = %+v, want (%g, %g)", max, wantMaxX, wantMaxY)
+	}
+}
+
+func TestCourtyardPolygonIncludesPolygons(t *testing.T) {
+	m := &Module{Polygons: []FpPoly{{
+		At:     Point2D{X: 0, Y: 0},
+		Points: []Point2D{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 1}, {X: -1, Y: 1}},
+		Layer:  "F.CrtYd",
+	}}}
+
+	hull := m.CourtyardPolygon()
+	if len(hull) == 0 {
+		t.Fatal("CourtyardPolygon() returned no points for a module containing only an fp_poly")
+	}
+}
+
+func TestMinPadClearanceAccountsForRotation(t *testing.T) {
+	// Two 2x1 rect pads placed 1.2 apart on X: unrotated they'd overlap
+	// on their long axis, but rotating both 90 degrees turns them into
+	// 1x2 rects with a real gap between them.
+	m := &Module{Pads: []Pad{
+		{Shape: "rect", Pos: Point2D{X: 0, Y: 0}, Size: Point2D{X: 2, Y: 1}, Rotation: 90},
+		{Shape: "rect", Pos: Point2D{X: 1.2, Y: 0}, Size: Point2D{X: 2, Y: 1}, Rotation: 90},
+	}}
+
+	clearance, _, _ := m.MinPadClearance()
+	if clearance <= 0 {
+		t.Fatalf("MinPadClearance() = %g, want > 0 once rotation is accounted for", clearance)
+	}
+}
@@ -0,0 +1,54 @@
+package mod
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestRoundTripGoldenLibrary decodes every .kicad_mod file in
+// testdata/sample.pretty, re-encodes it with MarshalKiCad, and decodes
+// the result again, asserting the two decodes agree. This only checks
+// that the struct survives a decode/encode/decode cycle unchanged; per
+// EncodeModule's doc comment, the encoded text itself is not asserted
+// to match the original file's formatting.
+func TestRoundTripGoldenLibrary(t *testing.T) {
+	paths, err := filepath.Glob("testdata/sample.pretty/*.kicad_mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no golden fixtures found under testdata/sample.pretty")
+	}
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			first, err := DecodeModule(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("decode original: %v", err)
+			}
+
+			encoded, err := first.MarshalKiCad()
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+
+			second, err := DecodeModule(bytes.NewReader(encoded))
+			if err != nil {
+				t.Fatalf("decode re-encoded output: %v\n%s", err, encoded)
+			}
+
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("round trip mismatch:\n first:  %+v\n second: %+v\nencoded:\n%s", first, second, encoded)
+			}
+		})
+	}
+}
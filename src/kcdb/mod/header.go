@@ -0,0 +1,89 @@
+package mod
+
+import (
+	"errors"
+	"io"
+
+	"github.com/nsf/sexp"
+)
+
+// ModuleHeader holds the subset of a Module's fields needed to build a
+// searchable footprint index, without the cost of decoding every pad
+// and graphic in the file.
+type ModuleHeader struct {
+	Name        string   `json:"name"`
+	Tedit       string   `json:"tedit"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Layer       string   `json:"layer"`
+}
+
+// DecodeModuleHeader extracts a module's Name, Tedit, Description,
+// Tags, and Layer. It still parses the entire .kicad_mod stream with
+// sexp.Parse (the sexp package has no incremental mode to stop early),
+// so it does not save parse time; the saving is skipping the
+// allocation of Pads and graphics that DecodeModule builds, which
+// matters when an index keeps headers for many footprints in memory
+// at once.
+func DecodeModuleHeader(r io.RuneReader) (*ModuleHeader, error) {
+	out := &ModuleHeader{}
+	ast, err := sexp.Parse(r, nil)
+
+	if !ast.IsList() {
+		return nil, errors.New("invalid format: expected s-expression list at top level")
+	}
+	if ast.NumChildren() != 1 {
+		return nil, errors.New("invalid format: top level list of size 1")
+	}
+	mainAST, _ := ast.Nth(0)
+	if !mainAST.IsList() {
+		return nil, errors.New("invalid format: expected s-expression list at 1st level")
+	}
+
+	if mainAST.NumChildren() < 3 {
+		return nil, errors.New("invalid format: missing minimum elements")
+	}
+	if s, err2 := sexp.Help(mainAST).Child(0).String(); err2 != nil || s != "module" {
+		return nil, errors.New("invalid format: missing module prefix")
+	}
+
+	out.Name, err = sexp.Help(mainAST).Child(1).String()
+	if err != nil {
+		return nil, errors.New("invalid format: expected string value for module name")
+	}
+
+	for i := 2; i < mainAST.NumChildren(); i++ {
+		n := sexp.Help(mainAST).Child(i)
+		if !n.IsList() || !n.Child(1).IsValid() {
+			continue
+		}
+		switch n.Child(0).MustString() {
+		case "layer":
+			out.Layer, err = n.Child(1).String()
+			if err != nil {
+				return nil, errors.New("invalid format: layer value must be a string")
+			}
+		case "tedit":
+			out.Tedit, err = n.Child(1).String()
+			if err != nil {
+				return nil, errors.New("invalid format: tedit value must be a string")
+			}
+		case "descr":
+			out.Description, err = n.Child(1).String()
+			if err != nil {
+				return nil, errors.New("invalid format: descr value must be a string")
+			}
+		case "tags":
+			for x := 1; x < n.MustNode().NumChildren(); x++ {
+				var t string
+				t, err = n.Child(1).String()
+				if err != nil {
+					return nil, errors.New("invalid format: tag value must be a string")
+				}
+				out.Tags = append(out.Tags, t)
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,41 @@
+package mod
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeModuleHeader(t *testing.T) {
+	src := `(module R_TEST (layer F.Cu) (tedit 5DD50112)
+  (descr "Resistor SMD 0603, KiCad 5 legacy format")
+  (tags resistor)
+  (attr smd)
+  (at 0 0)
+  (fp_line (start -1 -0.5) (end 1 -0.5) (layer F.SilkS) (width 0.12))
+  (pad 1 smd rect (at -0.75 0) (size 0.9 0.95) (layers F.Cu F.Paste F.Mask))
+)`
+
+	h, err := DecodeModuleHeader(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("DecodeModuleHeader() error = %v", err)
+	}
+
+	if h.Name != "R_TEST" {
+		t.Errorf("Name = %q, want %q", h.Name, "R_TEST")
+	}
+	if h.Layer != "F.Cu" {
+		t.Errorf("Layer = %q, want %q", h.Layer, "F.Cu")
+	}
+	if h.Tedit != "5DD50112" {
+		t.Errorf("Tedit = %q, want %q", h.Tedit, "5DD50112")
+	}
+	if h.Description != "Resistor SMD 0603, KiCad 5 legacy format" {
+		t.Errorf("Description = %q, want the descr stanza's text", h.Description)
+	}
+}
+
+func TestDecodeModuleHeaderRejectsInvalidFormat(t *testing.T) {
+	if _, err := DecodeModuleHeader(strings.NewReader("(not_a_module)")); err == nil {
+		t.Fatal("DecodeModuleHeader() error = nil, want error for a file missing the module prefix")
+	}
+}
@@ -0,0 +1,93 @@
+package mod
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Result is one footprint decoded by a LibraryWalker.
+type Result struct {
+	Path     string
+	Module   *Module
+	Warnings []string
+	Err      error
+}
+
+// LibraryWalker concurrently scans one or more directories (typically
+// `.pretty` footprint libraries) for `.kicad_mod` files and decodes
+// them through a bounded worker pool.
+type LibraryWalker struct {
+	// Concurrency is the number of files decoded at once. Defaults to 4.
+	Concurrency int
+	// Filter, if set, is called with each discovered file's path before
+	// it is decoded; returning false skips the file entirely.
+	Filter func(path string) bool
+}
+
+// Walk scans dirs for `*.kicad_mod` files and decodes them concurrently,
+// emitting one Result per file on the returned channel. The channel is
+// closed once every file has been processed.
+func (w *LibraryWalker) Walk(dirs ...string) <-chan Result {
+	results := make(chan Result)
+	concurrency := w.Concurrency
+	if concurrency < 1 {
+		concurrency = 4
+	}
+
+	paths := make(chan string)
+	go func() {
+		defer close(paths)
+		for _, dir := range dirs {
+			filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || !strings.HasSuffix(path, ".kicad_mod") {
+					return nil
+				}
+				if w.Filter != nil && !w.Filter(path) {
+					return nil
+				}
+				paths <- path
+				return nil
+			})
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				results <- decodeFile(path)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// decodeFile decodes path leniently: a library can contain thousands of
+// footprints written by many KiCad versions, and one file with a
+// stanza this package doesn't recognize shouldn't fail the whole scan.
+// Skipped stanzas are reported on Result.Warnings instead.
+func decodeFile(path string) Result {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{Path: path, Err: err}
+	}
+	defer f.Close()
+
+	m, err := DecodeModuleWith(bufio.NewReader(f), DecodeOptions{Strict: false})
+	if m != nil {
+		return Result{Path: path, Module: m, Warnings: m.Warnings, Err: err}
+	}
+	return Result{Path: path, Err: err}
+}
@@ -0,0 +1,41 @@
+package mod
+
+import (
+	"testing"
+)
+
+func TestLibraryWalkerDecodesEveryFootprint(t *testing.T) {
+	w := &LibraryWalker{}
+
+	var results []Result
+	for r := range w.Walk("testdata/sample.pretty") {
+		results = append(results, r)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2 (one per .kicad_mod file under testdata/sample.pretty)", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: Err = %v, want nil", r.Path, r.Err)
+		}
+		if r.Module == nil {
+			t.Errorf("%s: Module = nil, want a decoded module", r.Path)
+		}
+	}
+}
+
+func TestLibraryWalkerAppliesFilter(t *testing.T) {
+	w := &LibraryWalker{
+		Filter: func(path string) bool { return false },
+	}
+
+	var results []Result
+	for r := range w.Walk("testdata/sample.pretty") {
+		results = append(results, r)
+	}
+
+	if len(results) != 0 {
+		t.Fatalf("got %d results with a filter rejecting every path, want 0", len(results))
+	}
+}
@@ -22,6 +22,14 @@ type FpLine struct {
 	Width float64 `json:"width"`
 }
 
+// FpRect represents a graphical rectangle, introduced in KiCad 6.
+type FpRect struct {
+	Start Point2D `json:"start"`
+	End   Point2D `json:"end"`
+	Layer string  `json:"layer"`
+	Width float64 `json:"width"`
+}
+
 // FpCircle represents a graphical circle.
 type FpCircle struct {
 	Center Point2D `json:"center"`
@@ -41,24 +49,33 @@ type FpArc struct {
 
 // FpPoly represents a graphical polygon.
 type FpPoly struct {
-	At     Point2D   `json:"position"`
-	Points []Point2D `json:"points"`
-	Layer  string    `json:"layer"`
-	Width  float64   `json:"width"`
+	At       Point2D   `json:"position"`
+	Rotation float64   `json:"rotation,omitempty"`
+	Points   []Point2D `json:"points"`
+	Layer    string    `json:"layer"`
+	Width    float64   `json:"width"`
 }
 
 // FpText represents graphical text.
 type FpText struct {
-	Pos    Point2D `json:"position"`
-	Kind   string  `json:"kind"`
-	Value  string  `json:"value"`
-	Layer  string  `json:"layer"`
-	Hidden bool    `json:"hidden"`
+	Pos      Point2D `json:"position"`
+	Rotation float64 `json:"rotation,omitempty"`
+	Kind     string  `json:"kind"`
+	Value    string  `json:"value"`
+	Layer    string  `json:"layer"`
+	Hidden   bool    `json:"hidden"`
 
 	Size      Point2D `json:"size"`
 	Thickness float64 `json:"thickness"`
 }
 
+// PadPrimitive represents one graphical shape (currently just gr_poly)
+// making up a custom pad's outline, listed under its `primitives` stanza.
+type PadPrimitive struct {
+	Points []Point2D `json:"points"`
+	Width  float64   `json:"width"`
+}
+
 // Pad represents a pad in a component footprint.
 type Pad struct {
 	Pin   int    `json:"pin"`
@@ -66,9 +83,16 @@ type Pad struct {
 	Shape string `json:"shape"`
 	Drill Drill  `json:"drill"`
 
-	Pos    Point2D  `json:"position"`
-	Size   Point2D  `json:"size"`
-	Layers []string `json:"layers"`
+	Pos      Point2D  `json:"position"`
+	Rotation float64  `json:"rotation,omitempty"`
+	Size     Point2D  `json:"size"`
+	Layers   []string `json:"layers"`
+
+	RoundrectRRatio float64        `json:"roundrect_rratio,omitempty"`
+	Chamfer         []string       `json:"chamfer,omitempty"`
+	NetNum          int            `json:"net_num,omitempty"`
+	NetName         string         `json:"net_name,omitempty"`
+	Primitives      []PadPrimitive `json:"primitives,omitempty"`
 }
 
 // Drill represents pad drill parameters.
@@ -86,9 +110,16 @@ type Module struct {
 	Description string  `json:"description"`
 	Layer       string  `json:"layer"`
 	Position    Point2D `json:"position"`
+	Rotation    float64 `json:"rotation,omitempty"`
 	Clearance   float64 `json:"clearance,omitempty"`
 	Model       string  `json:"model"`
 
+	// Version and Generator identify the KiCad footprint-file format
+	// revision and the tool that wrote it; both are 0/empty for the
+	// legacy KiCad 5 format, which predates these fields.
+	Version   int    `json:"version,omitempty"`
+	Generator string `json:"generator,omitempty"`
+
 	SolderMaskMargin  float64 `json:"solder_mask_margin,omitempty"`
 	SolderPasteMargin float64 `json:"solder_paste_margin,omitempty"`
 	SolderPasteRatio  float64 `json:"solder_paste_ratio,omitempty"`
@@ -96,15 +127,41 @@ type Module struct {
 	Tags     []string   `json:"tags"`
 	Attrs    []string   `json:"attrs"`
 	Lines    []FpLine   `json:"lines"`
+	Rects    []FpRect   `json:"rects"`
 	Arcs     []FpArc    `json:"arcs"`
 	Circles  []FpCircle `json:"circles"`
 	Polygons []FpPoly   `json:"polygons"`
 	Texts    []FpText   `json:"texts"`
 	Pads     []Pad      `json:"pads"`
+
+	// Warnings collects unrecognized stanzas skipped while decoding
+	// with DecodeOptions.Strict set to false. It is always empty when
+	// decoded via DecodeModule or with Strict set to true.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
-// DecodeModule reads a .kicad_mod file from a reader.
+// DecodeOptions controls how DecodeModuleWith parses a .kicad_mod file.
+type DecodeOptions struct {
+	// Strict, when true, fails decoding on any top-level stanza the
+	// decoder does not recognize. When false, unknown top-level stanzas
+	// are skipped and noted in the returned Module's Warnings instead.
+	// Unknown sub-lists inside fp_* and pad stanzas are ignored in
+	// either mode.
+	Strict bool
+}
+
+// DecodeModule reads a .kicad_mod file from a reader, failing on any
+// top-level stanza it doesn't recognize. It is equivalent to
+// DecodeModuleWith(r, DecodeOptions{Strict: true}).
 func DecodeModule(r io.RuneReader) (*Module, error) {
+	return DecodeModuleWith(r, DecodeOptions{Strict: true})
+}
+
+// DecodeModuleWith reads a .kicad_mod file from a reader, applying
+// opts. It understands both the legacy KiCad 5 format and the KiCad
+// 6/7 format (rotated `at`, `roundrect_rratio`, `chamfer`, custom-pad
+// `primitives`, `fp_rect`, and `stroke` sub-lists).
+func DecodeModuleWith(r io.RuneReader, opts DecodeOptions) (*Module, error) {
 	out := &Module{}
 	ast, err := sexp.Parse(r, nil)
 
@@ -172,6 +229,19 @@ func DecodeModule(r io.RuneReader) (*Module, error) {
 				}
 			case "at":
 				out.Position = Point2D{X: n.Child(1).MustFloat64(), Y: n.Child(2).MustFloat64()}
+				if n.MustNode().NumChildren() > 3 {
+					out.Rotation = n.Child(3).MustFloat64()
+				}
+			case "version":
+				out.Version, err = n.Child(1).Int()
+				if err != nil {
+					return nil, errors.New("invalid format: version value must be an integer")
+				}
+			case "generator":
+				out.Generator, err = n.Child(1).String()
+				if err != nil {
+					return nil, errors.New("invalid format: generator value must be a string")
+				}
 			case "clearance":
 				out.Clearance = n.Child(1).MustFloat64()
 			case "solder_mask_margin":
@@ -191,6 +261,12 @@ func DecodeModule(r io.RuneReader) (*Module, error) {
 					return nil, err
 				}
 				out.Lines = append(out.Lines, line)
+			case "fp_rect":
+				rect, err := unmarshalFpRect(n)
+				if err != nil {
+					return nil, err
+				}
+				out.Rects = append(out.Rects, rect)
 			case "fp_circle":
 				c, err := unmarshalFpCircle(n)
 				if err != nil {
@@ -222,7 +298,10 @@ func DecodeModule(r io.RuneReader) (*Module, error) {
 				}
 				out.Pads = append(out.Pads, pad)
 			default:
-				return nil, errors.New("cannot handle expression: " + n.Child(0).MustString())
+				if opts.Strict {
+					return nil, errors.New("cannot handle expression: " + n.Child(0).MustString())
+				}
+				out.Warnings = append(out.Warnings, "skipped unrecognized stanza: "+n.Child(0).MustString())
 			}
 		}
 
@@ -243,6 +322,8 @@ func unmarshalFpArc(n sexp.Helper) (FpArc, error) {
 			arc.Layer = n.Child(x).Child(1).MustString()
 		case "width":
 			arc.Width = n.Child(x).Child(1).MustFloat64()
+		case "stroke":
+			arc.Width = decodeStrokeWidth(n.Child(x))
 		case "angle":
 			arc.Angle = n.Child(x).Child(1).MustFloat64()
 		}
@@ -262,6 +343,8 @@ func unmarshalFpCircle(n sexp.Helper) (FpCircle, error) {
 			circle.Layer = n.Child(x).Child(1).MustString()
 		case "width":
 			circle.Width = n.Child(x).Child(1).MustFloat64()
+		case "stroke":
+			circle.Width = decodeStrokeWidth(n.Child(x))
 		}
 	}
 	return circle, nil
@@ -273,6 +356,9 @@ func unmarshalFpPoly(n sexp.Helper) (FpPoly, error) {
 		switch n.Child(x).Child(0).MustString() {
 		case "at":
 			p.At = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
+			if n.Child(x).MustNode().NumChildren() > 3 {
+				p.Rotation = n.Child(x).Child(3).MustFloat64()
+			}
 		case "pts":
 			for i := 1; i < n.Child(x).MustNode().NumChildren(); i++ {
 				if n.Child(x).Child(i).Child(0).MustString() == "xy" {
@@ -285,6 +371,8 @@ func unmarshalFpPoly(n sexp.Helper) (FpPoly, error) {
 			p.Layer = n.Child(x).Child(1).MustString()
 		case "width":
 			p.Width = n.Child(x).Child(1).MustFloat64()
+		case "stroke":
+			p.Width = decodeStrokeWidth(n.Child(x))
 		}
 	}
 	return p, nil
@@ -302,11 +390,43 @@ func unmarshalFpLine(n sexp.Helper) (FpLine, error) {
 			line.Layer = n.Child(x).Child(1).MustString()
 		case "width":
 			line.Width = n.Child(x).Child(1).MustFloat64()
+		case "stroke":
+			line.Width = decodeStrokeWidth(n.Child(x))
 		}
 	}
 	return line, nil
 }
 
+func unmarshalFpRect(n sexp.Helper) (FpRect, error) {
+	rect := FpRect{}
+	for x := 1; x < n.MustNode().NumChildren(); x++ {
+		switch n.Child(x).Child(0).MustString() {
+		case "start":
+			rect.Start = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
+		case "end":
+			rect.End = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
+		case "layer":
+			rect.Layer = n.Child(x).Child(1).MustString()
+		case "width":
+			rect.Width = n.Child(x).Child(1).MustFloat64()
+		case "stroke":
+			rect.Width = decodeStrokeWidth(n.Child(x))
+		}
+	}
+	return rect, nil
+}
+
+// decodeStrokeWidth extracts the width from a KiCad 6+ `stroke` sub-list,
+// e.g. `(stroke (width 0.12) (type solid))`.
+func decodeStrokeWidth(n sexp.Helper) float64 {
+	for x := 1; x < n.MustNode().NumChildren(); x++ {
+		if n.Child(x).IsList() && n.Child(x).Child(0).MustString() == "width" {
+			return n.Child(x).Child(1).MustFloat64()
+		}
+	}
+	return 0
+}
+
 func unmarshalFpText(n sexp.Helper) (FpText, error) {
 	txt := FpText{
 		Kind:  n.Child(1).MustString(),
@@ -327,6 +447,9 @@ func unmarshalFpText(n sexp.Helper) (FpText, error) {
 		switch n.Child(x).Child(0).MustString() {
 		case "at":
 			txt.Pos = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
+			if n.Child(x).MustNode().NumChildren() > 3 {
+				txt.Rotation = n.Child(x).Child(3).MustFloat64()
+			}
 		case "layer":
 			txt.Layer = n.Child(x).Child(1).MustString()
 		case "effects":
@@ -385,8 +508,13 @@ func unmarshalPad(n sexp.Helper) (Pad, error) {
 
 	for x := 4; x < n.MustNode().NumChildren(); x++ {
 		switch n.Child(x).Child(0).MustString() {
+		case "zone_connect":
+			// ignore
 		case "at":
 			pad.Pos = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
+			if n.Child(x).MustNode().NumChildren() > 3 {
+				pad.Rotation = n.Child(x).Child(3).MustFloat64()
+			}
 		case "size":
 			pad.Size = Point2D{X: n.Child(x).Child(1).MustFloat64(), Y: n.Child(x).Child(2).MustFloat64()}
 		case "drill":
@@ -401,7 +529,52 @@ func unmarshalPad(n sexp.Helper) (Pad, error) {
 				pad.Layers = append(pad.Layers, s.Child(i).MustString())
 			}
 
+		case "roundrect_rratio":
+			pad.RoundrectRRatio = n.Child(x).Child(1).MustFloat64()
+		case "chamfer":
+			s := n.Child(x)
+			for i := 1; i < s.MustNode().NumChildren(); i++ {
+				pad.Chamfer = append(pad.Chamfer, s.Child(i).MustString())
+			}
+		case "net":
+			pad.NetNum, err = n.Child(x).Child(1).Int()
+			if err != nil {
+				return pad, errors.New("invalid format: net number must be an integer")
+			}
+			pad.NetName = n.Child(x).Child(2).MustString()
+		case "primitives":
+			pad.Primitives, err = unmarshalPadPrimitives(n.Child(x))
+			if err != nil {
+				return pad, err
+			}
 		}
 	}
 	return pad, nil
 }
+
+func unmarshalPadPrimitives(n sexp.Helper) ([]PadPrimitive, error) {
+	var prims []PadPrimitive
+	for x := 1; x < n.MustNode().NumChildren(); x++ {
+		if n.Child(x).Child(0).MustString() != "gr_poly" {
+			continue
+		}
+		prim := PadPrimitive{}
+		g := n.Child(x)
+		for y := 1; y < g.MustNode().NumChildren(); y++ {
+			switch g.Child(y).Child(0).MustString() {
+			case "pts":
+				pts := g.Child(y)
+				for i := 1; i < pts.MustNode().NumChildren(); i++ {
+					if pts.Child(i).Child(0).MustString() != "xy" {
+						return nil, fmt.Errorf("cannot handle expression of type %q in gr_poly.pts stanza", pts.Child(i).Child(0).MustString())
+					}
+					prim.Points = append(prim.Points, Point2D{X: pts.Child(i).Child(1).MustFloat64(), Y: pts.Child(i).Child(2).MustFloat64()})
+				}
+			case "width":
+				prim.Width = g.Child(y).Child(1).MustFloat64()
+			}
+		}
+		prims = append(prims, prim)
+	}
+	return prims, nil
+}
@@ -0,0 +1,75 @@
+package mod
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeModuleStrictFailsOnUnknownStanza(t *testing.T) {
+	src := `(module R_TEST (layer F.Cu) (tedit 0)
+  (frobnicate 1)
+)`
+
+	if _, err := DecodeModule(strings.NewReader(src)); err == nil {
+		t.Fatal("DecodeModule() error = nil, want error on unrecognized stanza")
+	}
+}
+
+func TestDecodeModuleWithLenientSkipsUnknownStanza(t *testing.T) {
+	src := `(module R_TEST (layer F.Cu) (tedit 0)
+  (frobnicate 1)
+)`
+
+	m, err := DecodeModuleWith(strings.NewReader(src), DecodeOptions{Strict: false})
+	if err != nil {
+		t.Fatalf("DecodeModuleWith(Strict: false) error = %v, want nil", err)
+	}
+	if len(m.Warnings) != 1 {
+		t.Fatalf("Warnings = %v, want exactly one warning", m.Warnings)
+	}
+	if !strings.Contains(m.Warnings[0], "frobnicate") {
+		t.Fatalf("Warnings[0] = %q, want it to mention the skipped stanza", m.Warnings[0])
+	}
+}
+
+func TestDecodePadChamferAndPrimitives(t *testing.T) {
+	src := `(module R_TEST (layer F.Cu) (tedit 0)
+  (pad 1 smd custom (at 0 0) (size 1 1)
+    (chamfer top_left top_right)
+    (primitives
+      (gr_poly (pts (xy 0 0) (xy 1 0) (xy 1 1)) (width 0.1))
+    )
+  )
+)`
+
+	m, err := DecodeModule(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("DecodeModule() error = %v", err)
+	}
+	if len(m.Pads) != 1 {
+		t.Fatalf("Pads = %d, want 1", len(m.Pads))
+	}
+
+	pad := m.Pads[0]
+	wantChamfer := []string{"top_left", "top_right"}
+	if len(pad.Chamfer) != len(wantChamfer) || pad.Chamfer[0] != wantChamfer[0] || pad.Chamfer[1] != wantChamfer[1] {
+		t.Fatalf("Chamfer = %v, want %v", pad.Chamfer, wantChamfer)
+	}
+
+	if len(pad.Primitives) != 1 {
+		t.Fatalf("Primitives = %d, want 1", len(pad.Primitives))
+	}
+	prim := pad.Primitives[0]
+	if prim.Width != 0.1 {
+		t.Fatalf("Primitives[0].Width = %g, want 0.1", prim.Width)
+	}
+	wantPts := []Point2D{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}
+	if len(prim.Points) != len(wantPts) {
+		t.Fatalf("Primitives[0].Points = %v, want %v", prim.Points, wantPts)
+	}
+	for i, p := range wantPts {
+		if prim.Points[i] != p {
+			t.Fatalf("Primitives[0].Points[%d] = %+v, want %+v", i, prim.Points[i], p)
+		}
+	}
+}
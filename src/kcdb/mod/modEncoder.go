@@ -0,0 +1,219 @@
+package mod
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EncodeModule writes m to w in KiCad's .kicad_mod s-expression format.
+//
+// The output is readable by KiCad and by DecodeModule, and decoding it
+// back reproduces m field-for-field, but the formatting is this
+// package's own (one stanza per line, floats trimmed the way KiCad
+// trims them) rather than a byte-for-byte reproduction of KiCad's own
+// plotter output — quoting, stanza ordering, and multi-line stanzas
+// (e.g. KiCad 6's "stroke" sub-list, multi-line pads) are not
+// preserved, so re-encoding a file KiCad wrote will not diff cleanly
+// against the original. Tags are written as one space-joined quoted
+// string and Warnings are not written at all, so a Module with more
+// than one tag or with warnings decodes back with those fields changed.
+func EncodeModule(w io.Writer, m *Module) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "(module %s", quote(m.Name))
+	if m.Layer != "" {
+		fmt.Fprintf(bw, " (layer %s)", quote(m.Layer))
+	}
+	if m.Tedit != "" {
+		fmt.Fprintf(bw, " (tedit %s)", m.Tedit)
+	}
+	bw.WriteString("\n")
+
+	if m.Version != 0 {
+		fmt.Fprintf(bw, "  (version %d)\n", m.Version)
+	}
+	if m.Generator != "" {
+		fmt.Fprintf(bw, "  (generator %s)\n", m.Generator)
+	}
+	if m.Description != "" {
+		fmt.Fprintf(bw, "  (descr %s)\n", quote(m.Description))
+	}
+	if len(m.Tags) > 0 {
+		fmt.Fprintf(bw, "  (tags %s)\n", quote(strings.Join(m.Tags, " ")))
+	}
+	if len(m.Attrs) > 0 {
+		fmt.Fprintf(bw, "  (attr %s)\n", strings.Join(m.Attrs, " "))
+	}
+	if m.Rotation != 0 {
+		fmt.Fprintf(bw, "  (at %s %s %s)\n", ff(m.Position.X), ff(m.Position.Y), ff(m.Rotation))
+	} else {
+		fmt.Fprintf(bw, "  (at %s %s)\n", ff(m.Position.X), ff(m.Position.Y))
+	}
+	if m.SolderMaskMargin != 0 {
+		fmt.Fprintf(bw, "  (solder_mask_margin %s)\n", ff(m.SolderMaskMargin))
+	}
+	if m.SolderPasteMargin != 0 {
+		fmt.Fprintf(bw, "  (solder_paste_margin %s)\n", ff(m.SolderPasteMargin))
+	}
+	if m.SolderPasteRatio != 0 {
+		fmt.Fprintf(bw, "  (solder_paste_ratio %s)\n", ff(m.SolderPasteRatio))
+	}
+	if m.Clearance != 0 {
+		fmt.Fprintf(bw, "  (clearance %s)\n", ff(m.Clearance))
+	}
+
+	for _, l := range m.Lines {
+		writeFpLine(bw, l)
+	}
+	for _, r := range m.Rects {
+		writeFpRect(bw, r)
+	}
+	for _, a := range m.Arcs {
+		writeFpArc(bw, a)
+	}
+	for _, c := range m.Circles {
+		writeFpCircle(bw, c)
+	}
+	for _, p := range m.Polygons {
+		writeFpPoly(bw, p)
+	}
+	for _, t := range m.Texts {
+		writeFpText(bw, t)
+	}
+	for _, p := range m.Pads {
+		writeFpPad(bw, p)
+	}
+
+	if m.Model != "" {
+		fmt.Fprintf(bw, "  (model %s)\n", quote(m.Model))
+	}
+
+	bw.WriteString(")\n")
+
+	return bw.Flush()
+}
+
+// MarshalKiCad encodes m into the KiCad .kicad_mod s-expression format.
+func (m *Module) MarshalKiCad() ([]byte, error) {
+	var sb strings.Builder
+	if err := EncodeModule(&sb, m); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+func writeFpLine(bw *bufio.Writer, l FpLine) {
+	fmt.Fprintf(bw, "  (fp_line (start %s %s) (end %s %s) (layer %s) (width %s))\n",
+		ff(l.Start.X), ff(l.Start.Y), ff(l.End.X), ff(l.End.Y), quote(l.Layer), ff(l.Width))
+}
+
+func writeFpRect(bw *bufio.Writer, r FpRect) {
+	fmt.Fprintf(bw, "  (fp_rect (start %s %s) (end %s %s) (layer %s) (width %s))\n",
+		ff(r.Start.X), ff(r.Start.Y), ff(r.End.X), ff(r.End.Y), quote(r.Layer), ff(r.Width))
+}
+
+func writeFpCircle(bw *bufio.Writer, c FpCircle) {
+	fmt.Fprintf(bw, "  (fp_circle (center %s %s) (end %s %s) (layer %s) (width %s))\n",
+		ff(c.Center.X), ff(c.Center.Y), ff(c.End.X), ff(c.End.Y), quote(c.Layer), ff(c.Width))
+}
+
+func writeFpArc(bw *bufio.Writer, a FpArc) {
+	fmt.Fprintf(bw, "  (fp_arc (start %s %s) (end %s %s) (angle %s) (layer %s) (width %s))\n",
+		ff(a.Start.X), ff(a.Start.Y), ff(a.End.X), ff(a.End.Y), ff(a.Angle), quote(a.Layer), ff(a.Width))
+}
+
+func writeFpPoly(bw *bufio.Writer, p FpPoly) {
+	if p.Rotation != 0 {
+		fmt.Fprintf(bw, "  (fp_poly (at %s %s %s) (pts", ff(p.At.X), ff(p.At.Y), ff(p.Rotation))
+	} else {
+		fmt.Fprintf(bw, "  (fp_poly (at %s %s) (pts", ff(p.At.X), ff(p.At.Y))
+	}
+	for _, pt := range p.Points {
+		fmt.Fprintf(bw, " (xy %s %s)", ff(pt.X), ff(pt.Y))
+	}
+	fmt.Fprintf(bw, ") (layer %s) (width %s))\n", quote(p.Layer), ff(p.Width))
+}
+
+func writeFpText(bw *bufio.Writer, t FpText) {
+	if t.Rotation != 0 {
+		fmt.Fprintf(bw, "  (fp_text %s %s (at %s %s %s) (layer %s)", t.Kind, quote(t.Value), ff(t.Pos.X), ff(t.Pos.Y), ff(t.Rotation), quote(t.Layer))
+	} else {
+		fmt.Fprintf(bw, "  (fp_text %s %s (at %s %s) (layer %s)", t.Kind, quote(t.Value), ff(t.Pos.X), ff(t.Pos.Y), quote(t.Layer))
+	}
+	if t.Hidden {
+		bw.WriteString(" hide")
+	}
+	fmt.Fprintf(bw, "\n    (effects (font (size %s %s) (thickness %s)))\n  )\n",
+		ff(t.Size.X), ff(t.Size.Y), ff(t.Thickness))
+}
+
+func writeFpPad(bw *bufio.Writer, p Pad) {
+	at := fmt.Sprintf("(at %s %s)", ff(p.Pos.X), ff(p.Pos.Y))
+	if p.Rotation != 0 {
+		at = fmt.Sprintf("(at %s %s %s)", ff(p.Pos.X), ff(p.Pos.Y), ff(p.Rotation))
+	}
+	if p.Pin != 0 {
+		fmt.Fprintf(bw, "  (pad %d %s %s %s (size %s %s)", p.Pin, p.Kind, p.Shape, at, ff(p.Size.X), ff(p.Size.Y))
+	} else {
+		fmt.Fprintf(bw, "  (pad \"\" %s %s %s (size %s %s)", p.Kind, p.Shape, at, ff(p.Size.X), ff(p.Size.Y))
+	}
+	writeDrill(bw, p.Drill)
+	if len(p.Layers) > 0 {
+		fmt.Fprintf(bw, " (layers %s)", strings.Join(p.Layers, " "))
+	}
+	if p.RoundrectRRatio != 0 {
+		fmt.Fprintf(bw, " (roundrect_rratio %s)", ff(p.RoundrectRRatio))
+	}
+	if len(p.Chamfer) > 0 {
+		fmt.Fprintf(bw, " (chamfer %s)", strings.Join(p.Chamfer, " "))
+	}
+	if p.NetNum != 0 || p.NetName != "" {
+		fmt.Fprintf(bw, " (net %d %s)", p.NetNum, quote(p.NetName))
+	}
+	if len(p.Primitives) > 0 {
+		bw.WriteString(" (primitives")
+		for _, prim := range p.Primitives {
+			bw.WriteString(" (gr_poly (pts")
+			for _, pt := range prim.Points {
+				fmt.Fprintf(bw, " (xy %s %s)", ff(pt.X), ff(pt.Y))
+			}
+			fmt.Fprintf(bw, ") (width %s))", ff(prim.Width))
+		}
+		bw.WriteString(")")
+	}
+	bw.WriteString(")\n")
+}
+
+func writeDrill(bw *bufio.Writer, d Drill) {
+	switch {
+	case d.Kind != "":
+		fmt.Fprintf(bw, " (drill %s %s %s", d.Kind, ff(d.Ellipse.X), ff(d.Ellipse.Y))
+	case d.Scalar != 0:
+		fmt.Fprintf(bw, " (drill %s", ff(d.Scalar))
+	default:
+		return
+	}
+	if d.Offset.X != 0 || d.Offset.Y != 0 {
+		fmt.Fprintf(bw, " (offset %s %s)", ff(d.Offset.X), ff(d.Offset.Y))
+	}
+	bw.WriteString(")")
+}
+
+// ff formats a float the way KiCad's plotter does: fixed-point,
+// trimmed of trailing zeros and a dangling decimal point.
+func ff(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 6, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimRight(s, ".")
+	if s == "" || s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,503 @@
+// Package render rasterizes a parsed KiCad module into SVG or PNG
+// images so that a footprint can be previewed or diffed without
+// opening KiCad.
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/thomasesr/kcdb/src/kcdb/mod"
+)
+
+// Options controls how a Module is rendered.
+type Options struct {
+	// Scale converts KiCad mm units to pixels/SVG units.
+	Scale float64
+	// Padding (in pixels/SVG units) added around the module's bounding box.
+	Padding float64
+	// LayerColors maps a KiCad layer name (e.g. "F.SilkS") to the color
+	// it should be drawn in. Layers absent from this map use DefaultColor.
+	LayerColors map[string]color.Color
+	// DefaultColor is used for layers not present in LayerColors.
+	DefaultColor color.Color
+	// Layers, if non-empty, restricts rendering to the named layers.
+	// An empty set renders every layer present in the module.
+	Layers map[string]bool
+}
+
+// DefaultOptions returns sane defaults for previewing a footprint.
+func DefaultOptions() Options {
+	return Options{
+		Scale:        20,
+		Padding:      10,
+		DefaultColor: color.RGBA{R: 0xc8, G: 0xc8, B: 0xc8, A: 0xff},
+		LayerColors: map[string]color.Color{
+			"F.SilkS": color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff},
+			"B.SilkS": color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff},
+			"F.Cu":    color.RGBA{R: 0xc8, G: 0x3c, B: 0x3c, A: 0xff},
+			"B.Cu":    color.RGBA{R: 0x3c, G: 0x3c, B: 0xc8, A: 0xff},
+			"F.CrtYd": color.RGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff},
+			"B.CrtYd": color.RGBA{R: 0x50, G: 0x50, B: 0x50, A: 0xff},
+		},
+	}
+}
+
+func (o Options) colorFor(layer string) color.Color {
+	if c, ok := o.LayerColors[layer]; ok {
+		return c
+	}
+	return o.DefaultColor
+}
+
+// arcSteps is the number of segments used to approximate an FpArc.
+const arcSteps = 32
+
+func (o Options) visible(layer string) bool {
+	if len(o.Layers) == 0 {
+		return true
+	}
+	return o.Layers[layer]
+}
+
+// bounds returns the module's extent in mm, across every visible layer.
+func bounds(m *mod.Module, o Options) (min, max mod.Point2D) {
+	min = mod.Point2D{X: math.MaxFloat64, Y: math.MaxFloat64}
+	max = mod.Point2D{X: -math.MaxFloat64, Y: -math.MaxFloat64}
+	consider := func(p mod.Point2D) {
+		min.X, min.Y = math.Min(min.X, p.X), math.Min(min.Y, p.Y)
+		max.X, max.Y = math.Max(max.X, p.X), math.Max(max.Y, p.Y)
+	}
+	for _, l := range m.Lines {
+		if o.visible(l.Layer) {
+			consider(l.Start)
+			consider(l.End)
+		}
+	}
+	for _, r := range m.Rects {
+		if o.visible(r.Layer) {
+			consider(r.Start)
+			consider(r.End)
+		}
+	}
+	for _, a := range m.Arcs {
+		if o.visible(a.Layer) {
+			for _, p := range a.Sample(arcSteps) {
+				consider(p)
+			}
+		}
+	}
+	for _, c := range m.Circles {
+		if o.visible(c.Layer) {
+			r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+			consider(mod.Point2D{X: c.Center.X - r, Y: c.Center.Y - r})
+			consider(mod.Point2D{X: c.Center.X + r, Y: c.Center.Y + r})
+		}
+	}
+	for _, poly := range m.Polygons {
+		if !o.visible(poly.Layer) {
+			continue
+		}
+		for _, p := range poly.Outline() {
+			consider(p)
+		}
+	}
+	for _, p := range m.Pads {
+		layer := ""
+		if len(p.Layers) > 0 {
+			layer = p.Layers[0]
+		}
+		if !o.visible(layer) {
+			continue
+		}
+		for _, pt := range p.Outline() {
+			consider(pt)
+		}
+	}
+	if min.X > max.X {
+		min, max = mod.Point2D{}, mod.Point2D{}
+	}
+	return min, max
+}
+
+// ToSVG renders m as an SVG document to w.
+func ToSVG(w io.Writer, m *mod.Module, opts Options) error {
+	min, max := bounds(m, opts)
+	width := (max.X-min.X)*opts.Scale + 2*opts.Padding
+	height := (max.Y-min.Y)*opts.Scale + 2*opts.Padding
+
+	tx := func(x float64) float64 { return (x-min.X)*opts.Scale + opts.Padding }
+	ty := func(y float64) float64 { return (y-min.Y)*opts.Scale + opts.Padding }
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.2f\" height=\"%.2f\" viewBox=\"0 0 %.2f %.2f\">\n",
+		width, height, width, height)
+
+	for _, l := range m.Lines {
+		if !opts.visible(l.Layer) {
+			continue
+		}
+		fmt.Fprintf(bw, "  <line x1=\"%.3f\" y1=\"%.3f\" x2=\"%.3f\" y2=\"%.3f\" stroke=\"%s\" stroke-width=\"%.3f\"%s />\n",
+			tx(l.Start.X), ty(l.Start.Y), tx(l.End.X), ty(l.End.Y), svgColor(opts.colorFor(l.Layer)), l.Width*opts.Scale,
+			dashAttr(l.Width*opts.Scale, isSilkLayer(l.Layer)))
+	}
+	for _, r := range m.Rects {
+		if !opts.visible(r.Layer) {
+			continue
+		}
+		fmt.Fprintf(bw, "  <rect x=\"%.3f\" y=\"%.3f\" width=\"%.3f\" height=\"%.3f\" stroke=\"%s\" stroke-width=\"%.3f\" fill=\"none\"%s />\n",
+			math.Min(tx(r.Start.X), tx(r.End.X)), math.Min(ty(r.Start.Y), ty(r.End.Y)),
+			math.Abs(tx(r.End.X)-tx(r.Start.X)), math.Abs(ty(r.End.Y)-ty(r.Start.Y)),
+			svgColor(opts.colorFor(r.Layer)), r.Width*opts.Scale, dashAttr(r.Width*opts.Scale, isSilkLayer(r.Layer)))
+	}
+	for _, a := range m.Arcs {
+		if !opts.visible(a.Layer) {
+			continue
+		}
+		writeSVGPolyline(bw, a.Sample(arcSteps), tx, ty, opts.colorFor(a.Layer), a.Width*opts.Scale, false, isSilkLayer(a.Layer))
+	}
+	for _, c := range m.Circles {
+		if !opts.visible(c.Layer) {
+			continue
+		}
+		r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+		fmt.Fprintf(bw, "  <circle cx=\"%.3f\" cy=\"%.3f\" r=\"%.3f\" stroke=\"%s\" stroke-width=\"%.3f\" fill=\"none\"%s />\n",
+			tx(c.Center.X), ty(c.Center.Y), r*opts.Scale, svgColor(opts.colorFor(c.Layer)), c.Width*opts.Scale,
+			dashAttr(c.Width*opts.Scale, isSilkLayer(c.Layer)))
+	}
+	for _, p := range m.Polygons {
+		if !opts.visible(p.Layer) {
+			continue
+		}
+		writeSVGPolyline(bw, p.Outline(), tx, ty, opts.colorFor(p.Layer), p.Width*opts.Scale, true, false)
+	}
+	for _, t := range m.Texts {
+		if !opts.visible(t.Layer) || t.Hidden {
+			continue
+		}
+		fmt.Fprintf(bw, "  <text x=\"%.3f\" y=\"%.3f\" font-size=\"%.3f\" fill=\"%s\">%s</text>\n",
+			tx(t.Pos.X), ty(t.Pos.Y), t.Size.Y*opts.Scale, svgColor(opts.colorFor(t.Layer)), escapeXML(t.Value))
+	}
+	for _, p := range m.Pads {
+		writeSVGPad(bw, p, tx, ty, opts)
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+func writeSVGPolyline(bw *bufio.Writer, pts []mod.Point2D, tx, ty func(float64) float64, c color.Color, width float64, closed, dashed bool) {
+	if len(pts) == 0 {
+		return
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "M %.3f %.3f", tx(pts[0].X), ty(pts[0].Y))
+	for _, p := range pts[1:] {
+		fmt.Fprintf(&sb, " L %.3f %.3f", tx(p.X), ty(p.Y))
+	}
+	if closed {
+		sb.WriteString(" Z")
+	}
+	fill := "none"
+	if closed {
+		fill = svgColor(c)
+	}
+	fmt.Fprintf(bw, "  <path d=\"%s\" stroke=\"%s\" stroke-width=\"%.3f\" fill=\"%s\"%s />\n",
+		sb.String(), svgColor(c), width, fill, dashAttr(width, dashed))
+}
+
+// dashAttr returns the stroke-dasharray attribute silkscreen strokes
+// are plotted with in KiCad, scaled to the stroke's own width, or the
+// empty string for a solid stroke.
+func dashAttr(width float64, dashed bool) string {
+	if !dashed || width <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" stroke-dasharray=\"%.3f %.3f\"", width*4, width*2)
+}
+
+func writeSVGPad(bw *bufio.Writer, p mod.Pad, tx, ty func(float64) float64, opts Options) {
+	layer := ""
+	if len(p.Layers) > 0 {
+		layer = p.Layers[0]
+	}
+	if !opts.visible(layer) {
+		return
+	}
+	col := svgColor(opts.colorFor(layer))
+	cx, cy := tx(p.Pos.X), ty(p.Pos.Y)
+	w, h := p.Size.X*opts.Scale, p.Size.Y*opts.Scale
+
+	// Shapes and the drill hole are emitted unrotated, in a <g> rotated
+	// about the pad's own center, so the SVG renderer applies p.Rotation
+	// (and carries the drill offset along with it) instead of us having
+	// to re-derive every point by hand.
+	if p.Rotation != 0 {
+		fmt.Fprintf(bw, "  <g transform=\"rotate(%.3f %.3f %.3f)\">\n", p.Rotation, cx, cy)
+	}
+
+	switch p.Shape {
+	case "circle":
+		fmt.Fprintf(bw, "  <circle cx=\"%.3f\" cy=\"%.3f\" r=\"%.3f\" fill=\"%s\" />\n", cx, cy, w/2, col)
+	case "oval":
+		fmt.Fprintf(bw, "  <ellipse cx=\"%.3f\" cy=\"%.3f\" rx=\"%.3f\" ry=\"%.3f\" fill=\"%s\" />\n", cx, cy, w/2, h/2, col)
+	case "roundrect":
+		fmt.Fprintf(bw, "  <rect x=\"%.3f\" y=\"%.3f\" width=\"%.3f\" height=\"%.3f\" rx=\"%.3f\" fill=\"%s\" />\n",
+			cx-w/2, cy-h/2, w, h, math.Min(w, h)*0.2, col)
+	default: // "rect" and anything unrecognized draws as a rectangle
+		fmt.Fprintf(bw, "  <rect x=\"%.3f\" y=\"%.3f\" width=\"%.3f\" height=\"%.3f\" fill=\"%s\" />\n", cx-w/2, cy-h/2, w, h, col)
+	}
+
+	if r := drillRadius(p.Drill); r > 0 {
+		dx, dy := tx(p.Pos.X+p.Drill.Offset.X), ty(p.Pos.Y+p.Drill.Offset.Y)
+		fmt.Fprintf(bw, "  <circle cx=\"%.3f\" cy=\"%.3f\" r=\"%.3f\" fill=\"black\" />\n", dx, dy, r*opts.Scale)
+	}
+
+	if p.Rotation != 0 {
+		bw.WriteString("  </g>\n")
+	}
+}
+
+func drillRadius(d mod.Drill) float64 {
+	switch {
+	case d.Scalar > 0:
+		return d.Scalar / 2
+	case d.Ellipse.X > 0 || d.Ellipse.Y > 0:
+		return math.Min(d.Ellipse.X, d.Ellipse.Y) / 2
+	default:
+		return 0
+	}
+}
+
+func svgColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+func escapeXML(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return r.Replace(s)
+}
+
+// ToPNG rasterizes m to a PNG image written to w.
+func ToPNG(w io.Writer, m *mod.Module, opts Options) error {
+	min, max := bounds(m, opts)
+	width := int((max.X-min.X)*opts.Scale+2*opts.Padding) + 1
+	height := int((max.Y-min.Y)*opts.Scale+2*opts.Padding) + 1
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{A: 0}), image.Point{}, draw.Src)
+
+	tx := func(x float64) float64 { return (x-min.X)*opts.Scale + opts.Padding }
+	ty := func(y float64) float64 { return (y-min.Y)*opts.Scale + opts.Padding }
+
+	for _, l := range m.Lines {
+		if opts.visible(l.Layer) {
+			drawPolyline(img, []mod.Point2D{l.Start, l.End}, tx, ty, l.Width*opts.Scale, isSilkLayer(l.Layer), opts.colorFor(l.Layer))
+		}
+	}
+	for _, r := range m.Rects {
+		if opts.visible(r.Layer) {
+			drawPolyline(img, []mod.Point2D{
+				r.Start, {X: r.End.X, Y: r.Start.Y}, r.End, {X: r.Start.X, Y: r.End.Y}, r.Start,
+			}, tx, ty, r.Width*opts.Scale, isSilkLayer(r.Layer), opts.colorFor(r.Layer))
+		}
+	}
+	for _, a := range m.Arcs {
+		if opts.visible(a.Layer) {
+			drawPolyline(img, a.Sample(arcSteps), tx, ty, a.Width*opts.Scale, isSilkLayer(a.Layer), opts.colorFor(a.Layer))
+		}
+	}
+	for _, c := range m.Circles {
+		if opts.visible(c.Layer) {
+			r := math.Hypot(c.End.X-c.Center.X, c.End.Y-c.Center.Y)
+			drawCircle(img, tx(c.Center.X), ty(c.Center.Y), r*opts.Scale, c.Width*opts.Scale, isSilkLayer(c.Layer), opts.colorFor(c.Layer))
+		}
+	}
+	for _, poly := range m.Polygons {
+		if !opts.visible(poly.Layer) {
+			continue
+		}
+		outline := poly.Outline()
+		pts := make([]mod.Point2D, len(outline))
+		for i, pt := range outline {
+			pts[i] = mod.Point2D{X: tx(pt.X), Y: ty(pt.Y)}
+		}
+		fillPolygon(img, pts, opts.colorFor(poly.Layer))
+	}
+	for _, p := range m.Pads {
+		layer := ""
+		if len(p.Layers) > 0 {
+			layer = p.Layers[0]
+		}
+		if !opts.visible(layer) {
+			continue
+		}
+		// Outline() already translates by Pos and rotates by Rotation, so
+		// the filled polygon follows the pad's placement exactly instead
+		// of assuming an axis-aligned rectangle or circle.
+		outline := p.Outline()
+		pts := make([]mod.Point2D, len(outline))
+		for i, pt := range outline {
+			pts[i] = mod.Point2D{X: tx(pt.X), Y: ty(pt.Y)}
+		}
+		fillPolygon(img, pts, opts.colorFor(layer))
+
+		if r := drillRadius(p.Drill); r > 0 {
+			offset := mod.RotateVector(p.Drill.Offset, p.Rotation)
+			fillCircle(img, tx(p.Pos.X+offset.X), ty(p.Pos.Y+offset.Y), r*opts.Scale, color.RGBA{A: 0xff})
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// isSilkLayer reports whether layer is one of the silkscreen layers,
+// which KiCad always plots with a dashed stroke so designators and
+// outlines remain legible once printed.
+func isSilkLayer(layer string) bool {
+	return layer == "F.SilkS" || layer == "B.SilkS"
+}
+
+// drawPolyline strokes pts (in board coordinates) at the given width
+// (in device pixels), optionally dashed, following the same convention
+// as the SVG output's stroke-width/stroke-dasharray.
+func drawPolyline(img *image.RGBA, pts []mod.Point2D, tx, ty func(float64) float64, width float64, dashed bool, c color.Color) {
+	screen := make([]mod.Point2D, len(pts))
+	for i, p := range pts {
+		screen[i] = mod.Point2D{X: tx(p.X), Y: ty(p.Y)}
+	}
+	strokePolyline(img, screen, width, dashed, c)
+}
+
+func drawCircle(img *image.RGBA, cx, cy, r, width float64, dashed bool, c color.Color) {
+	const steps = 64
+	pts := make([]mod.Point2D, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		t := 2 * math.Pi * float64(i) / steps
+		pts = append(pts, mod.Point2D{X: cx + r*math.Cos(t), Y: cy + r*math.Sin(t)})
+	}
+	strokePolyline(img, pts, width, dashed, c)
+}
+
+// strokePolyline draws a width-aware stroke along pts (already in
+// device pixels). When dashed, the stroke alternates 4*width "on"
+// segments with 2*width "off" segments, with the dash phase carried
+// continuously across the whole polyline rather than reset per segment.
+func strokePolyline(img *image.RGBA, pts []mod.Point2D, width float64, dashed bool, c color.Color) {
+	if len(pts) < 2 {
+		return
+	}
+	if width < 1 {
+		width = 1
+	}
+	if !dashed {
+		for i := 1; i < len(pts); i++ {
+			strokeSegment(img, pts[i-1], pts[i], width, c)
+		}
+		return
+	}
+
+	dashLen, gapLen := width*4, width*2
+	on := true
+	remaining := dashLen
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		segLen := math.Hypot(b.X-a.X, b.Y-a.Y)
+		for t := 0.0; t < segLen; {
+			if remaining <= 0 {
+				on = !on
+				if on {
+					remaining = dashLen
+				} else {
+					remaining = gapLen
+				}
+			}
+			step := math.Min(remaining, segLen-t)
+			if on {
+				t0, t1 := t/segLen, (t+step)/segLen
+				strokeSegment(img,
+					mod.Point2D{X: a.X + (b.X-a.X)*t0, Y: a.Y + (b.Y-a.Y)*t0},
+					mod.Point2D{X: a.X + (b.X-a.X)*t1, Y: a.Y + (b.Y-a.Y)*t1},
+					width, c)
+			}
+			t += step
+			remaining -= step
+		}
+	}
+}
+
+// strokeSegment draws one width-wide, round-capped segment between a
+// and b (device pixels) by filling the rectangle between their
+// perpendicular offsets and capping each end with a filled circle.
+func strokeSegment(img *image.RGBA, a, b mod.Point2D, width float64, c color.Color) {
+	length := math.Hypot(b.X-a.X, b.Y-a.Y)
+	if length == 0 {
+		fillCircle(img, a.X, a.Y, width/2, c)
+		return
+	}
+	nx, ny := -(b.Y-a.Y)/length*width/2, (b.X-a.X)/length*width/2
+	fillPolygon(img, []mod.Point2D{
+		{X: a.X + nx, Y: a.Y + ny}, {X: b.X + nx, Y: b.Y + ny},
+		{X: b.X - nx, Y: b.Y - ny}, {X: a.X - nx, Y: a.Y - ny},
+	}, c)
+	fillCircle(img, a.X, a.Y, width/2, c)
+	fillCircle(img, b.X, b.Y, width/2, c)
+}
+
+// fillCircle fills a solid disc of radius r centered at (cx, cy),
+// approximated as a polygon; used for drill holes and round stroke caps.
+func fillCircle(img *image.RGBA, cx, cy, r float64, c color.Color) {
+	const steps = 24
+	pts := make([]mod.Point2D, steps)
+	for i := 0; i < steps; i++ {
+		t := 2 * math.Pi * float64(i) / steps
+		pts[i] = mod.Point2D{X: cx + r*math.Cos(t), Y: cy + r*math.Sin(t)}
+	}
+	fillPolygon(img, pts, c)
+}
+
+// fillPolygon fills a closed polygon using a scanline fill.
+func fillPolygon(img *image.RGBA, pts []mod.Point2D, c color.Color) {
+	if len(pts) < 3 {
+		return
+	}
+	minY, maxY := pts[0].Y, pts[0].Y
+	for _, p := range pts {
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	for y := int(minY); y <= int(maxY); y++ {
+		fy := float64(y) + 0.5
+		var xs []float64
+		for i := range pts {
+			a, b := pts[i], pts[(i+1)%len(pts)]
+			if (a.Y <= fy && b.Y > fy) || (b.Y <= fy && a.Y > fy) {
+				xs = append(xs, a.X+(fy-a.Y)/(b.Y-a.Y)*(b.X-a.X))
+			}
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			x0, x1 := xs[i], xs[i+1]
+			if x1 < x0 {
+				x0, x1 = x1, x0
+			}
+			for x := int(x0); x <= int(x1); x++ {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/thomasesr/kcdb/src/kcdb/mod"
+)
+
+func TestToSVGDashesSilkscreenOnly(t *testing.T) {
+	m := &mod.Module{
+		Lines: []mod.FpLine{
+			{Start: mod.Point2D{X: -2, Y: 0}, End: mod.Point2D{X: 2, Y: 0}, Width: 0.15, Layer: "F.SilkS"},
+			{Start: mod.Point2D{X: -2, Y: 1}, End: mod.Point2D{X: 2, Y: 1}, Width: 0.15, Layer: "F.Cu"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ToSVG(&buf, m, DefaultOptions()); err != nil {
+		t.Fatalf("ToSVG() error = %v", err)
+	}
+	out := buf.String()
+
+	lines := strings.Split(out, "\n")
+	var silkLine, cuLine string
+	for _, l := range lines {
+		switch {
+		case strings.Contains(l, `"#f0f0f0"`):
+			silkLine = l
+		case strings.Contains(l, `"#c83c3c"`):
+			cuLine = l
+		}
+	}
+	if !strings.Contains(silkLine, "stroke-dasharray") {
+		t.Errorf("F.SilkS line missing stroke-dasharray: %s", silkLine)
+	}
+	if strings.Contains(cuLine, "stroke-dasharray") {
+		t.Errorf("F.Cu line should not be dashed: %s", cuLine)
+	}
+}
+
+func TestToPNGRendersWideRotatedPad(t *testing.T) {
+	m := &mod.Module{
+		Pads: []mod.Pad{{Shape: "rect", Pos: mod.Point2D{X: 0, Y: 0}, Size: mod.Point2D{X: 2, Y: 1}, Rotation: 90, Layers: []string{"F.Cu"}}},
+	}
+
+	var buf bytes.Buffer
+	if err := ToPNG(&buf, m, DefaultOptions()); err != nil {
+		t.Fatalf("ToPNG() error = %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	// A 90 degree rotation swaps the pad's 2x1 footprint to 1x2, so the
+	// image should come out taller than it is wide.
+	bounds := img.Bounds()
+	if bounds.Dy() <= bounds.Dx() {
+		t.Errorf("rendered image is %dx%d, want taller than wide for a 90-degree-rotated 2x1 pad", bounds.Dx(), bounds.Dy())
+	}
+
+	// Something opaque should have been drawn at the image's center.
+	_, _, _, a := img.At(bounds.Dx()/2, bounds.Dy()/2).RGBA()
+	if a == 0 {
+		t.Error("expected an opaque pixel at the center of the rendered pad")
+	}
+}